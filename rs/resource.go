@@ -1,45 +1,78 @@
 package rs
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/user"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/rightscale/terraform-provider-rs/rs/rsc"
 )
 
 func resourceRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutRead)
+	defer cancel()
+
 	client := m.(rsc.Client)
 	loc, err := locator(d)
 	if err != nil {
 		return err
 	}
-	res, err := client.Get(loc)
+	res, err := client.Get(ctx, loc)
 	if err != nil {
 		return handleRSCError(d, err)
 	}
 	for k, v := range res.Fields {
 		d.Set(k, v)
 	}
+
+	tags, err := client.Tags(ctx, loc)
+	if err != nil {
+		return handleRSCError(d, err)
+	}
+	d.Set("tags", tags)
 	return nil
 }
 
 func resourceDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutDelete)
+	defer cancel()
+
 	client := m.(rsc.Client)
 	loc, err := locator(d)
 	if err != nil {
 		return err
 	}
-	return client.Delete(loc)
+	lockID, err := client.Lock(ctx, loc, lockOwner(), "delete")
+	if err != nil {
+		return err
+	}
+	if err := client.Delete(ctx, loc, lockID); err != nil {
+		client.Unlock(ctx, loc, lockID)
+		return err
+	}
+	return nil
 }
 
 func resourceExists(d *schema.ResourceData, m interface{}) (bool, error) {
+	ctx, cancel := resourceContext(d, schema.TimeoutRead)
+	defer cancel()
+
 	client := m.(rsc.Client)
 	loc, err := locator(d)
 	if err != nil {
 		return false, err
 	}
-	res, err := client.Get(loc)
+	res, err := client.Get(ctx, loc)
+	if err == rsc.ErrNotFound {
+		// genuinely gone; any other error, including a retryable API blip
+		// (which Get has already retried if the client is wrapped with
+		// rsc.WithRetry), is returned below rather than reported as gone
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
@@ -65,4 +98,92 @@ func locator(d *schema.ResourceData) (*rsc.Locator, error) {
 		return nil, fmt.Errorf("invalid resource ID %q", d.Id())
 	}
 	return &rsc.Locator{Namespace: parts[0], Href: parts[1]}, nil
+}
+
+// newImporter returns a schema.ResourceImporter for a resource whose ID is a
+// rsc.Locator rendered as "namespace:href". It accepts that same form as
+// well as a bare href, in which case defaultNamespace is assumed, validates
+// the href against the RightScale API and hydrates every schema field from
+// the returned resource so "terraform import" works for resources created
+// outside Terraform.
+func newImporter(defaultNamespace string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		State: func(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			namespace, href := defaultNamespace, d.Id()
+			if parts := strings.SplitN(d.Id(), ":", 2); len(parts) == 2 {
+				namespace, href = parts[0], parts[1]
+			}
+			loc := &rsc.Locator{Namespace: namespace, Href: href}
+
+			ctx, cancel := resourceContext(d, schema.TimeoutRead)
+			defer cancel()
+			client := m.(rsc.Client)
+			res, err := client.Get(ctx, loc)
+			if err != nil {
+				return nil, fmt.Errorf("importing %q: %s", d.Id(), err)
+			}
+
+			d.SetId(namespace + ":" + href)
+			for k, v := range res.Fields {
+				d.Set(k, v)
+			}
+
+			// hydrate tags the same way resourceRead does, so an imported
+			// taggable resource doesn't show a spurious "add all tags" diff
+			// on the next plan
+			tags, err := client.Tags(ctx, loc)
+			if err != nil {
+				return nil, fmt.Errorf("importing %q: %s", d.Id(), err)
+			}
+			d.Set("tags", tags)
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// reservedTagNamespace is the tag namespace used by rsc.Client.Lock to
+// record advisory locks (see rsc.Client's doc comment). User-supplied tags
+// must not be allowed to collide with it.
+const reservedTagNamespace = "terraform:"
+
+// validateTagNotReserved is a schema.Schema ValidateFunc that rejects tags
+// in the "terraform:" namespace so that the generic tag subsystem cannot be
+// used to spoof or clobber another run's advisory lock.
+func validateTagNotReserved(v interface{}, k string) (warns []string, errs []error) {
+	tag, _ := v.(string)
+	if strings.HasPrefix(tag, reservedTagNamespace) {
+		errs = append(errs, fmt.Errorf("%s: tag %q uses the reserved %q namespace, which is used internally for advisory locking", k, tag, reservedTagNamespace))
+	}
+	return warns, errs
+}
+
+// lockOwner identifies the caller of rsc.Client.Lock, mirroring the "Who"
+// field Terraform records in its own state locks so that a lock left behind
+// by a failed run can be traced back to the user and host that took it.
+func lockOwner() string {
+	host, _ := os.Hostname()
+	if u, err := user.Current(); err == nil {
+		return u.Username + "@" + host
+	}
+	return "unknown@" + host
+}
+
+// defaultResourceTimeout bounds a resource operation when the resource's
+// Timeouts block does not configure one explicitly.
+const defaultResourceTimeout = 20 * time.Minute
+
+// resourceContext builds the context.Context passed to rsc.Client for a
+// single Create/Read/Update/Delete call, bounded by the resource's
+// schema.Resource.Timeouts entry for key. The caller must call the returned
+// cancel function once the operation completes.
+//
+// This would ideally derive from the provider's own StopContext so that
+// Terraform's Stop() (Ctrl-C) cancels in-flight calls too; that plumbing
+// lives in the provider's meta/configuration and is out of scope here.
+func resourceContext(d *schema.ResourceData, key string) (context.Context, context.CancelFunc) {
+	timeout := d.Timeout(key)
+	if timeout <= 0 {
+		timeout = defaultResourceTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
 }
\ No newline at end of file
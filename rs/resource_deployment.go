@@ -1,7 +1,10 @@
 package rs
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/rightscale/terraform-provider-rs/rs/rsc"
@@ -28,6 +31,17 @@ var deploymentSchema = map[string]*schema.Schema{
 		Type:        schema.TypeBool,
 		Optional:    true,
 	},
+	"lock_id": &schema.Schema{
+		Description: "ID of the advisory lock currently held on the deployment by this resource, if any",
+		Type:        schema.TypeString,
+		Computed:    true,
+	},
+	"tags": &schema.Schema{
+		Description: `tags attached to the deployment, each formatted as "ns:predicate=value"`,
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateTagNotReserved},
+	},
 	"server_tag_scope": &schema.Schema{
 		Description:  "routing scope for tags for servers in the deployment",
 		Type:         schema.TypeString,
@@ -45,16 +59,26 @@ var deploymentSchema = map[string]*schema.Schema{
 
 func resourceDeployment() *schema.Resource {
 	return &schema.Resource{
-		Schema: deploymentSchema,
-		Read:   resourceRead,
-		Exists: resourceExists,
-		Delete: resourceDelete, // can fail if deployment is locked - that's what we want
-		Create: resourceDeploymentCreate,
-		Update: resourceDeploymentUpdate,
+		Schema:   deploymentSchema,
+		Read:     resourceRead,
+		Exists:   resourceExists,
+		Delete:   resourceDelete, // can fail if deployment is locked - that's what we want
+		Create:   resourceDeploymentCreate,
+		Update:   resourceDeploymentUpdate,
+		Importer: newImporter("rs_cm"),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 	}
 }
 
 func resourceDeploymentCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutCreate)
+	defer cancel()
+
 	var mustLock bool
 	{
 		locked, ok := d.GetOk("locked")
@@ -62,7 +86,7 @@ func resourceDeploymentCreate(d *schema.ResourceData, m interface{}) error {
 	}
 
 	client := m.(rsc.Client)
-	res, err := client.Create("rs_cm", "deployment", deploymentFields(d))
+	res, err := client.Create(ctx, "rs_cm", "deployment", deploymentFields(d))
 	if err != nil {
 		return err
 	}
@@ -70,22 +94,56 @@ func resourceDeploymentCreate(d *schema.ResourceData, m interface{}) error {
 		d.Set(k, v)
 	}
 
+	// Acquire an advisory lock on the newly created deployment before
+	// touching it any further, so that a concurrent Terraform run targeting
+	// the same deployment (e.g. imported independently) cannot race us.
+	lockID, err := client.Lock(ctx, res.Locator, lockOwner(), "create")
+	if err != nil {
+		client.Delete(ctx, res.Locator, "")
+		return err
+	}
+	d.Set("lock_id", lockID)
+
+	if err := reconcileTags(ctx, d, client, res.Locator, lockID); err != nil {
+		return rollbackCreate(ctx, client, res.Locator, lockID, err)
+	}
+
 	if mustLock {
-		if err := updateLock(d, client); err != nil {
-			// Attempt to delete previously created deployment, ignore errors
-			client.Delete(res.Locator)
-			return err
+		if err := updateLock(ctx, d, client, res.Locator, lockID); err != nil {
+			return rollbackCreate(ctx, client, res.Locator, lockID, err)
 		}
 		d.Set("locked", true)
 	}
 
+	if err := client.Unlock(ctx, res.Locator, lockID); err != nil {
+		return rollbackCreate(ctx, client, res.Locator, lockID, err)
+	}
+	d.Set("lock_id", "")
+
 	// set ID last so Terraform does not assume the deployment has been
 	// created until all operations have completed successfully.
 	d.SetId(res.Locator.Namespace + ":" + res.Locator.Href)
 	return nil
 }
 
+// rollbackCreate unwinds a partially created deployment: it deletes loc
+// while lockID is still held and then releases the lock. A failed Delete is
+// folded into the original error instead of being discarded so operators are
+// not left with an orphaned deployment they don't know about; the lock is
+// released either way, and a failed Unlock is not reported.
+func rollbackCreate(ctx context.Context, client rsc.Client, loc *rsc.Locator, lockID string, cause error) error {
+	err := client.Delete(ctx, loc, lockID)
+	client.Unlock(ctx, loc, lockID)
+	if err != nil {
+		return fmt.Errorf("%s (also failed to roll back partially created deployment: %s)", cause, err)
+	}
+	return cause
+}
+
 func resourceDeploymentUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
 	d.Partial(true)
 
 	client := m.(rsc.Client)
@@ -94,33 +152,98 @@ func resourceDeploymentUpdate(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
+	lockID, err := client.Lock(ctx, loc, lockOwner(), "update")
+	if err != nil {
+		return err
+	}
+	defer client.Unlock(ctx, loc, lockID)
+
 	// update lock
-	if err := updateLock(d, client); err != nil {
+	if err := updateLock(ctx, d, client, loc, lockID); err != nil {
 		return handleError(d, err)
 	}
 	d.SetPartial("locked")
 
 	// then the other fields
-	if err := client.Update(loc, deploymentFields(d)); err != nil {
+	if err := client.Update(ctx, loc, lockID, deploymentFields(d)); err != nil {
 		return handleError(d, err)
 	}
 
+	if err := reconcileTags(ctx, d, client, loc, lockID); err != nil {
+		return handleError(d, err)
+	}
+	d.SetPartial("tags")
+
 	d.Partial(false)
 	return nil
 }
 
+// reconcileTags diffs the old and new values of the "tags" field and issues
+// a single Tag call adding the tags that were added and removing the ones
+// that were dropped. It is a no-op when tags are unchanged, which also
+// covers resource creation when no tags are configured. lockID must be the
+// advisory lock currently held on loc, same as Update/Delete/Run.
+func reconcileTags(ctx context.Context, d *schema.ResourceData, client rsc.Client, loc *rsc.Locator, lockID string) error {
+	old, new := d.GetChange("tags")
+	add, remove := diffTags(setStrings(old), setStrings(new))
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	return client.Tag(ctx, loc, lockID, add, remove)
+}
+
+// diffTags computes the tags to add and remove to turn oldTags into newTags.
+func diffTags(oldTags, newTags []string) (add, remove []string) {
+	inOld := make(map[string]bool, len(oldTags))
+	for _, t := range oldTags {
+		inOld[t] = true
+	}
+	inNew := make(map[string]bool, len(newTags))
+	for _, t := range newTags {
+		inNew[t] = true
+	}
+	for _, t := range newTags {
+		if !inOld[t] {
+			add = append(add, t)
+		}
+	}
+	for _, t := range oldTags {
+		if !inNew[t] {
+			remove = append(remove, t)
+		}
+	}
+	return add, remove
+}
+
+// setStrings converts a *schema.Set of strings (as returned by
+// ResourceData.Get/GetChange for a TypeSet field) to a []string. It returns
+// nil for any other value, including nil.
+func setStrings(v interface{}) []string {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return nil
+	}
+	list := set.List()
+	tags := make([]string, len(list))
+	for i, t := range list {
+		tags[i] = t.(string)
+	}
+	return tags
+}
+
 // updateLock is a helper function that takes care of locking or unlocking the
 // deployment according to the value of the "locked" resource data field.
-func updateLock(d *schema.ResourceData, client rsc.Client) error {
-	loc, err := locator(d)
-	if err != nil {
-		return err
-	}
+// loc must be passed in rather than derived from d.Id() because
+// resourceDeploymentCreate calls this before d.SetId has been called.
+// lockID must be the advisory lock currently held on the deployment; the
+// call is refused if it does not match. The RCL run polls ctx and aborts the
+// underlying HTTP request if it is canceled.
+func updateLock(ctx context.Context, d *schema.ResourceData, client rsc.Client, loc *rsc.Locator, lockID string) error {
 	lock := d.Get("locked").(bool)
 	if lock {
-		return client.Run(loc, "@res.lock()")
+		return client.Run(ctx, loc, lockID, "@res.lock()")
 	}
-	return client.Run(loc, "@res.unlock()")
+	return client.Run(ctx, loc, lockID, "@res.unlock()")
 }
 
 func deploymentFields(d *schema.ResourceData) rsc.Fields {
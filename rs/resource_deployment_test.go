@@ -0,0 +1,179 @@
+package rs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rightscale/terraform-provider-rs/rs/rsc"
+)
+
+// fakeClient is a minimal rsc.Client used to unit test rollback/lock
+// ordering logic without a real Cloud Management backend.
+type fakeClient struct {
+	unlockErr error
+	deleteErr error
+
+	unlocked bool
+	deleted  bool
+}
+
+func (c *fakeClient) Create(ctx context.Context, namespace, resType string, fields rsc.Fields) (*rsc.Resource, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeClient) Get(ctx context.Context, loc *rsc.Locator) (*rsc.Resource, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeClient) Update(ctx context.Context, loc *rsc.Locator, lockID string, fields rsc.Fields) error {
+	return errors.New("not implemented")
+}
+
+func (c *fakeClient) Delete(ctx context.Context, loc *rsc.Locator, lockID string) error {
+	c.deleted = true
+	return c.deleteErr
+}
+
+func (c *fakeClient) Run(ctx context.Context, loc *rsc.Locator, lockID, rcl string) error {
+	return errors.New("not implemented")
+}
+
+func (c *fakeClient) Lock(ctx context.Context, loc *rsc.Locator, who, operation string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (c *fakeClient) Unlock(ctx context.Context, loc *rsc.Locator, lockID string) error {
+	c.unlocked = true
+	return c.unlockErr
+}
+
+func (c *fakeClient) Tag(ctx context.Context, loc *rsc.Locator, lockID string, tagsToAdd, tagsToRemove []string) error {
+	return errors.New("not implemented")
+}
+
+func (c *fakeClient) Tags(ctx context.Context, loc *rsc.Locator) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRollbackCreate(t *testing.T) {
+	loc := &rsc.Locator{Namespace: "rs_cm", Href: "/api/deployments/1"}
+	cause := errors.New("reconcileTags failed")
+
+	t.Run("unlock and delete succeed", func(t *testing.T) {
+		client := &fakeClient{}
+		err := rollbackCreate(context.Background(), client, loc, "lock-1", cause)
+		if err != cause {
+			t.Fatalf("rollbackCreate() = %v, want original cause %v", err, cause)
+		}
+		if !client.unlocked || !client.deleted {
+			t.Fatalf("rollbackCreate() did not unlock (%v) and delete (%v)", client.unlocked, client.deleted)
+		}
+	})
+
+	t.Run("delete also fails", func(t *testing.T) {
+		deleteErr := errors.New("delete failed")
+		client := &fakeClient{deleteErr: deleteErr}
+		err := rollbackCreate(context.Background(), client, loc, "lock-1", cause)
+		if err == nil || err == cause {
+			t.Fatalf("rollbackCreate() = %v, want cause wrapped with rollback failure", err)
+		}
+		if !strings.Contains(err.Error(), cause.Error()) || !strings.Contains(err.Error(), deleteErr.Error()) {
+			t.Fatalf("rollbackCreate() = %q, want it to mention both %q and %q", err, cause, deleteErr)
+		}
+		if !client.unlocked {
+			t.Fatalf("rollbackCreate() did not attempt to unlock after the failed delete")
+		}
+	})
+
+	t.Run("unlock fails but delete succeeds", func(t *testing.T) {
+		// Unlock's error is not surfaced: a failed Unlock on a resource
+		// that has just been deleted is not worth reporting over cause.
+		client := &fakeClient{unlockErr: errors.New("unlock failed")}
+		err := rollbackCreate(context.Background(), client, loc, "lock-1", cause)
+		if err != cause {
+			t.Fatalf("rollbackCreate() = %v, want original cause %v", err, cause)
+		}
+		if !client.deleted {
+			t.Fatalf("rollbackCreate() did not delete despite the failed unlock")
+		}
+	})
+}
+
+func TestDiffTags(t *testing.T) {
+	cases := []struct {
+		name        string
+		old, new    []string
+		add, remove []string
+	}{
+		{
+			name: "no change",
+			old:  []string{"ns:a=1", "ns:b=2"},
+			new:  []string{"ns:a=1", "ns:b=2"},
+		},
+		{
+			name: "add only",
+			old:  []string{"ns:a=1"},
+			new:  []string{"ns:a=1", "ns:b=2"},
+			add:  []string{"ns:b=2"},
+		},
+		{
+			name:   "remove only",
+			old:    []string{"ns:a=1", "ns:b=2"},
+			new:    []string{"ns:a=1"},
+			remove: []string{"ns:b=2"},
+		},
+		{
+			name:   "add and remove",
+			old:    []string{"ns:a=1", "ns:b=2"},
+			new:    []string{"ns:a=1", "ns:c=3"},
+			add:    []string{"ns:c=3"},
+			remove: []string{"ns:b=2"},
+		},
+		{
+			name: "both empty",
+		},
+		{
+			name: "old empty",
+			new:  []string{"ns:a=1"},
+			add:  []string{"ns:a=1"},
+		},
+		{
+			name:   "new empty",
+			old:    []string{"ns:a=1"},
+			remove: []string{"ns:a=1"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			add, remove := diffTags(c.old, c.new)
+			if !sameElements(add, c.add) {
+				t.Errorf("diffTags() add = %v, want %v", add, c.add)
+			}
+			if !sameElements(remove, c.remove) {
+				t.Errorf("diffTags() remove = %v, want %v", remove, c.remove)
+			}
+		})
+	}
+}
+
+// sameElements reports whether got and want contain the same elements,
+// ignoring order; diffTags keeps the order of its input slices, and in
+// reconcileTags those come from a schema.Set, whose order carries no meaning.
+func sameElements(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	counts := make(map[string]int, len(want))
+	for _, w := range want {
+		counts[w]++
+	}
+	for _, g := range got {
+		counts[g]--
+		if counts[g] < 0 {
+			return false
+		}
+	}
+	return true
+}
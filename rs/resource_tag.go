@@ -0,0 +1,95 @@
+package rs
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/rightscale/terraform-provider-rs/rs/rsc"
+)
+
+var tagSchema = map[string]*schema.Schema{
+	"resource_href": &schema.Schema{
+		Description: "href of the resource the tag is attached to",
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+	},
+	"tag": &schema.Schema{
+		Description:  `tag to attach to the resource, formatted as "ns:predicate=value"`,
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validateTagNotReserved,
+	},
+}
+
+func resourceTag() *schema.Resource {
+	return &schema.Resource{
+		Schema: tagSchema,
+		Create: resourceTagCreate,
+		Read:   resourceTagRead,
+		Delete: resourceTagDelete,
+	}
+}
+
+func resourceTagCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	client := m.(rsc.Client)
+	loc := tagLocator(d)
+	tag := d.Get("tag").(string)
+
+	lockID, err := client.Lock(ctx, loc, lockOwner(), "tag-create")
+	if err != nil {
+		return err
+	}
+	defer client.Unlock(ctx, loc, lockID)
+
+	if err := client.Tag(ctx, loc, lockID, []string{tag}, nil); err != nil {
+		return err
+	}
+	d.SetId(loc.Href + ":" + tag)
+	return nil
+}
+
+func resourceTagRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	client := m.(rsc.Client)
+	loc := tagLocator(d)
+	tags, err := client.Tags(ctx, loc)
+	if err != nil {
+		return handleRSCError(d, err)
+	}
+	tag := d.Get("tag").(string)
+	for _, t := range tags {
+		if t == tag {
+			return nil
+		}
+	}
+	// the resource or the tag itself is gone
+	d.SetId("")
+	return nil
+}
+
+func resourceTagDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := resourceContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	client := m.(rsc.Client)
+	loc := tagLocator(d)
+
+	lockID, err := client.Lock(ctx, loc, lockOwner(), "tag-delete")
+	if err != nil {
+		return err
+	}
+	defer client.Unlock(ctx, loc, lockID)
+
+	return client.Tag(ctx, loc, lockID, nil, []string{d.Get("tag").(string)})
+}
+
+// tagLocator builds the rsc.Locator of the resource a rs_cm_tag is attached
+// to; Cloud Management tags are always namespaced "rs_cm".
+func tagLocator(d *schema.ResourceData) *rsc.Locator {
+	return &rsc.Locator{Namespace: "rs_cm", Href: d.Get("resource_href").(string)}
+}
@@ -0,0 +1,228 @@
+package rsc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+// RetryConfig controls the backoff policy applied by WithRetry.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of attempts after the initial one.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry; it doubles on every
+	// subsequent attempt (full jitter is then applied) up to MaxWait.
+	BaseDelay time.Duration
+	// MaxWait caps the delay between retries.
+	MaxWait time.Duration
+}
+
+// DefaultRetryConfig is used when a provider config does not override
+// max_retries / retry_max_wait.
+//
+// Reading max_retries / retry_max_wait from the provider config and
+// building a RetryConfig from them, then wrapping the concrete rsc.Client
+// with WithRetry when the provider configures itself, both belong in
+// provider.go, which (like the StopContext plumbing noted in
+// resourceContext) is not part of this chunk. DefaultRetryConfig is what
+// WithRetry falls back to until that wiring exists.
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries: 8,
+	BaseDelay:  500 * time.Millisecond,
+	MaxWait:    30 * time.Second,
+}
+
+// WithRetry wraps client so that calls which fail with a retryable error
+// (network errors, 5xx, 429, or an RCL "resource busy"/"locked" error) are
+// retried with exponential backoff and full jitter, honoring ctx
+// cancellation between attempts. Errors that are not retryable, including
+// ErrNotFound, are returned immediately.
+func WithRetry(client Client, cfg RetryConfig) Client {
+	return &retryingClient{client: client, cfg: cfg}
+}
+
+type retryingClient struct {
+	client Client
+	cfg    RetryConfig
+}
+
+func (c *retryingClient) Create(ctx context.Context, namespace, resType string, fields Fields) (res *Resource, err error) {
+	err = c.do(ctx, func() error {
+		res, err = c.client.Create(ctx, namespace, resType, fields)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryingClient) Get(ctx context.Context, loc *Locator) (res *Resource, err error) {
+	err = c.do(ctx, func() error {
+		res, err = c.client.Get(ctx, loc)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryingClient) Update(ctx context.Context, loc *Locator, lockID string, fields Fields) error {
+	return c.do(ctx, func() error {
+		return c.client.Update(ctx, loc, lockID, fields)
+	})
+}
+
+func (c *retryingClient) Delete(ctx context.Context, loc *Locator, lockID string) error {
+	return c.do(ctx, func() error {
+		return c.client.Delete(ctx, loc, lockID)
+	})
+}
+
+func (c *retryingClient) Run(ctx context.Context, loc *Locator, lockID, rcl string) error {
+	return c.do(ctx, func() error {
+		return c.client.Run(ctx, loc, lockID, rcl)
+	})
+}
+
+func (c *retryingClient) Lock(ctx context.Context, loc *Locator, who, operation string) (lockID string, err error) {
+	err = c.do(ctx, func() error {
+		lockID, err = c.client.Lock(ctx, loc, who, operation)
+		return err
+	})
+	return lockID, err
+}
+
+func (c *retryingClient) Unlock(ctx context.Context, loc *Locator, lockID string) error {
+	return c.do(ctx, func() error {
+		return c.client.Unlock(ctx, loc, lockID)
+	})
+}
+
+func (c *retryingClient) Tag(ctx context.Context, loc *Locator, lockID string, tagsToAdd, tagsToRemove []string) error {
+	return c.do(ctx, func() error {
+		return c.client.Tag(ctx, loc, lockID, tagsToAdd, tagsToRemove)
+	})
+}
+
+func (c *retryingClient) Tags(ctx context.Context, loc *Locator) (tags []string, err error) {
+	err = c.do(ctx, func() error {
+		tags, err = c.client.Tags(ctx, loc)
+		return err
+	})
+	return tags, err
+}
+
+// do runs op, retrying with jittered exponential backoff as long as op
+// returns a retryable error, ctx is not done and the retry budget remains.
+func (c *retryingClient) do(ctx context.Context, op func() error) error {
+	cfg := c.cfg
+	if cfg.MaxRetries == 0 && cfg.BaseDelay == 0 && cfg.MaxWait == 0 {
+		cfg = DefaultRetryConfig
+	}
+
+	var err error
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if err = op(); err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt == cfg.MaxRetries {
+			break
+		}
+		delay := retryDelay(cfg, attempt, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return err
+}
+
+// retryDelay returns how long do should wait before the next attempt: the
+// server-specified Retry-After on a 429 HTTPError, if present, otherwise the
+// jittered exponential backoff. Either way the result is capped at
+// effectiveMaxWait(cfg).
+func retryDelay(cfg RetryConfig, attempt int, err error) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		if wait := effectiveMaxWait(cfg); httpErr.RetryAfter > wait {
+			return wait
+		}
+		return httpErr.RetryAfter
+	}
+	return backoff(cfg, attempt)
+}
+
+// backoff returns the full-jitter exponential delay for the given attempt
+// (0-indexed), capped at effectiveMaxWait(cfg).
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	maxWait := effectiveMaxWait(cfg)
+	max := cfg.BaseDelay << uint(attempt)
+	if max <= 0 || max > maxWait {
+		max = maxWait
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// effectiveMaxWait returns cfg.MaxWait, falling back to
+// DefaultRetryConfig.MaxWait when cfg.MaxWait is not set. do only swaps in
+// DefaultRetryConfig wholesale when every RetryConfig field is zero, so a
+// caller-supplied cfg with a non-zero MaxRetries or BaseDelay but a zero
+// MaxWait would otherwise make rand.Int63n panic on a non-positive bound.
+func effectiveMaxWait(cfg RetryConfig) time.Duration {
+	if cfg.MaxWait <= 0 {
+		return DefaultRetryConfig.MaxWait
+	}
+	return cfg.MaxWait
+}
+
+// RetryableError wraps an error from the RightScale API known to be
+// transient (5xx, 429, or RCL reporting the resource is busy/locked).
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// HTTPError represents a non-2xx response from the RightScale API. Callers
+// of rsc.Client should return one of these (rather than a bare
+// fmt.Errorf) so that IsRetryable can classify the failure off the actual
+// status code instead of guessing from the error message.
+type HTTPError struct {
+	StatusCode int
+	// RetryAfter is the parsed value of a 429 response's Retry-After header,
+	// zero if the response did not send one (or isn't a 429). When set,
+	// retryDelay waits this long instead of computing its own backoff.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *HTTPError) Error() string { return e.Err.Error() }
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// IsRetryable reports whether err is worth retrying: a RetryableError, an
+// HTTPError with a transient status code (429, 500, 502, 503, 504), a
+// network-level error, or an RCL error reporting the resource is busy or
+// locked. ErrNotFound is never retryable.
+func IsRetryable(err error) bool {
+	if err == nil || err == ErrNotFound {
+		return false
+	}
+	if _, ok := err.(*RetryableError); ok {
+		return true
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case 429, 500, 502, 503, 504:
+			return true
+		default:
+			return false
+		}
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "resource busy") || strings.Contains(msg, "resource locked")
+}
@@ -0,0 +1,84 @@
+package rsc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not found", ErrNotFound, false},
+		{"generic error", errors.New("boom"), false},
+		{"retryable wrapper", &RetryableError{Err: errors.New("busy")}, true},
+		{"http 429", &HTTPError{StatusCode: 429, Err: errors.New("too many requests")}, true},
+		{"http 500", &HTTPError{StatusCode: 500, Err: errors.New("internal error")}, true},
+		{"http 503", &HTTPError{StatusCode: 503, Err: errors.New("unavailable")}, true},
+		{"http 404", &HTTPError{StatusCode: 404, Err: errors.New("not found")}, false},
+		{"http 400 with retryable-looking digits in message", &HTTPError{StatusCode: 400, Err: errors.New("invalid href /api/instances/1500")}, false},
+		{"rcl resource busy", errors.New("resource busy, try again"), true},
+		{"rcl resource locked", errors.New("resource locked by another process"), true},
+		{"permanent error that happens to mention 429", errors.New("quota exceeded: limit 429 per account"), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsRetryable(c.err); got != c.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestBackoffNeverExceedsMaxWait(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 500 * time.Millisecond, MaxWait: 30 * time.Second}
+	for attempt := 0; attempt < 20; attempt++ {
+		for i := 0; i < 100; i++ {
+			d := backoff(cfg, attempt)
+			if d < 0 || d > cfg.MaxWait {
+				t.Fatalf("backoff(attempt=%d) = %v, want in [0, %v]", attempt, d, cfg.MaxWait)
+			}
+		}
+	}
+}
+
+func TestBackoffZeroMaxWaitDoesNotPanic(t *testing.T) {
+	// MaxRetries/BaseDelay set but MaxWait left at its zero value: do only
+	// substitutes DefaultRetryConfig when every field is zero, so backoff
+	// must fall back on its own rather than call rand.Int63n(0).
+	cfg := RetryConfig{MaxRetries: 3, BaseDelay: 500 * time.Millisecond}
+	for attempt := 0; attempt < 5; attempt++ {
+		d := backoff(cfg, attempt)
+		if d < 0 || d > DefaultRetryConfig.MaxWait {
+			t.Fatalf("backoff(attempt=%d) = %v, want in [0, %v]", attempt, d, DefaultRetryConfig.MaxWait)
+		}
+	}
+}
+
+func TestRetryDelayHonorsRetryAfter(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 500 * time.Millisecond, MaxWait: 30 * time.Second}
+	err := &HTTPError{StatusCode: 429, Err: errors.New("too many requests"), RetryAfter: 5 * time.Second}
+	if d := retryDelay(cfg, 0, err); d != 5*time.Second {
+		t.Errorf("retryDelay with RetryAfter = %v, want %v", d, 5*time.Second)
+	}
+}
+
+func TestRetryDelayCapsRetryAfterAtMaxWait(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 500 * time.Millisecond, MaxWait: 10 * time.Second}
+	err := &HTTPError{StatusCode: 429, Err: errors.New("too many requests"), RetryAfter: time.Minute}
+	if d := retryDelay(cfg, 0, err); d != cfg.MaxWait {
+		t.Errorf("retryDelay with over-long RetryAfter = %v, want capped at %v", d, cfg.MaxWait)
+	}
+}
+
+func TestRetryDelayFallsBackToBackoffWithoutRetryAfter(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 500 * time.Millisecond, MaxWait: 30 * time.Second}
+	err := &HTTPError{StatusCode: 500, Err: errors.New("internal error")}
+	if d := retryDelay(cfg, 0, err); d < 0 || d > cfg.MaxWait {
+		t.Errorf("retryDelay(attempt=0) = %v, want in [0, %v]", d, cfg.MaxWait)
+	}
+}
@@ -0,0 +1,67 @@
+// Package rsc implements the client used by the rs package to talk to the
+// RightScale Cloud Management API.
+package rsc
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Client.Get when the requested resource does
+// not exist in Cloud Management.
+var ErrNotFound = errors.New("rsc: resource not found")
+
+// Locator identifies a Cloud Management resource by namespace (e.g. "rs_cm")
+// and href (e.g. "/api/deployments/12345").
+type Locator struct {
+	Namespace string
+	Href      string
+}
+
+// Fields is the set of field values used to create or update a resource.
+type Fields map[string]interface{}
+
+// Resource is the result of a Create or Get call.
+type Resource struct {
+	Locator *Locator
+	Fields  map[string]interface{}
+}
+
+// Client is the interface used by the rs package to create, read, update
+// and delete Cloud Management resources.
+//
+// Every method takes a context.Context and must abort the underlying HTTP
+// request as soon as it is canceled, so that a hung call can be interrupted
+// by Terraform's Stop() instead of blocking the run indefinitely.
+//
+// Update, Delete, Run and Tag must be guarded by a lock acquired with Lock:
+// they refuse to proceed unless the lock ID passed in matches the one
+// currently held on the resource. Create takes no lock ID, since the
+// resource does not exist until it returns.
+type Client interface {
+	Create(ctx context.Context, namespace, resType string, fields Fields) (*Resource, error)
+	Get(ctx context.Context, loc *Locator) (*Resource, error)
+	Update(ctx context.Context, loc *Locator, lockID string, fields Fields) error
+	Delete(ctx context.Context, loc *Locator, lockID string) error
+	Run(ctx context.Context, loc *Locator, lockID, rcl string) error
+
+	// Lock acquires a named advisory lock on loc (recorded in Cloud
+	// Management as a "terraform:lock=<uuid>:<who>:<operation>" tag) and
+	// returns its ID. The lock ID must be presented to Update, Delete and
+	// Run to operate on loc until Unlock is called.
+	Lock(ctx context.Context, loc *Locator, who, operation string) (lockID string, err error)
+
+	// Unlock releases the lock previously returned by Lock. It is a no-op
+	// if the lock has already been released.
+	Unlock(ctx context.Context, loc *Locator, lockID string) error
+
+	// Tag reconciles tags on loc, issuing a single multi_add call for
+	// tagsToAdd followed by a single multi_delete call for tagsToRemove
+	// against Cloud Management's /api/tags endpoints. Tags are formatted
+	// as "ns:predicate=value". Like Update/Delete/Run, it is refused
+	// unless lockID matches the lock currently held on loc.
+	Tag(ctx context.Context, loc *Locator, lockID string, tagsToAdd, tagsToRemove []string) error
+
+	// Tags returns the tags currently attached to loc via /api/tags/multi_get.
+	Tags(ctx context.Context, loc *Locator) ([]string, error)
+}